@@ -0,0 +1,235 @@
+package gorm_soft_delete
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditEvent is the structured record published for every soft delete and
+// restore this package's clauses produce.
+type AuditEvent struct {
+	Table     string
+	PKColumns []string
+	PKValues  [][]interface{}
+	Actor     interface{}
+	Mode      SoftDeleteMode
+	At        time.Time
+	// Restore is true for a Restore call, false for a soft Delete.
+	Restore bool
+}
+
+// AuditSink receives AuditEvents as they happen. Register calls it from
+// inside the same transaction that wrote the change, before that
+// transaction commits, so a sink error rolls back the write instead of
+// leaving an unaudited soft delete or restore committed.
+type AuditSink interface {
+	OnSoftDelete(ctx context.Context, event AuditEvent) error
+	OnRestore(ctx context.Context, event AuditEvent) error
+}
+
+// Option configures Register.
+type Option func(*auditConfig)
+
+type auditConfig struct {
+	tables map[string]bool // nil means "every table"
+}
+
+// ForTables restricts auditing to the named tables. By default Register
+// audits every soft delete and restore regardless of table.
+func ForTables(tables ...string) Option {
+	return func(c *auditConfig) {
+		if c.tables == nil {
+			c.tables = map[string]bool{}
+		}
+		for _, t := range tables {
+			c.tables[t] = true
+		}
+	}
+}
+
+// Register installs callbacks that publish an AuditEvent to sink for every
+// soft delete and restore. A soft Delete never runs GORM's Update callback
+// chain - SoftDeleteDeleteClause.ModifyStatement builds its UPDATE SQL
+// inline and executes through Callback().Delete() like any other delete -
+// so soft deletes are audited from Delete().After("gorm:delete"), the step
+// after the UPDATE has run; Restore goes through an ordinary Updates()
+// call, so restores are audited from Update().After("gorm:update"). Both
+// hooks are additionally pinned Before("gorm:commit_or_rollback_transaction"),
+// the last step of either chain, so the publish step genuinely runs inside
+// the transaction that produced the event: a sink error reaches tx.AddError
+// before commit and rolls the write back, instead of running after commit
+// where it's too late to do anything but log.
+func Register(db *gorm.DB, sink AuditSink, opts ...Option) error {
+	cfg := &auditConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	publish := func(tx *gorm.DB) {
+		publishAuditEvents(tx, sink, cfg)
+	}
+
+	if err := db.Callback().Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").Register("soft_delete:audit_delete", publish); err != nil {
+		return err
+	}
+
+	return db.Callback().Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").Register("soft_delete:audit_restore", publish)
+}
+
+func publishAuditEvents(tx *gorm.DB, sink AuditSink, cfg *auditConfig) {
+	if tx.Error != nil || tx.Statement == nil {
+		return
+	}
+
+	events := pendingAuditEvents(tx.Statement.Context)
+	if len(events) == 0 {
+		return
+	}
+
+	if cfg.tables != nil && !cfg.tables[tx.Statement.Table] {
+		return
+	}
+
+	for _, event := range events {
+		var err error
+		if event.Restore {
+			err = sink.OnRestore(tx.Statement.Context, event)
+		} else {
+			err = sink.OnSoftDelete(tx.Statement.Context, event)
+		}
+		if err != nil {
+			tx.AddError(err)
+			return
+		}
+	}
+}
+
+// auditPendingKey is the context key under which SoftDeleteDeleteClause and
+// Restore stash the events they want published once their UPDATE has run,
+// before the surrounding transaction commits.
+type auditPendingKey struct{}
+
+func withPendingAuditEvent(ctx context.Context, event AuditEvent) context.Context {
+	if events, ok := ctx.Value(auditPendingKey{}).(*[]AuditEvent); ok {
+		*events = append(*events, event)
+		return ctx
+	}
+	events := []AuditEvent{event}
+	return context.WithValue(ctx, auditPendingKey{}, &events)
+}
+
+func pendingAuditEvents(ctx context.Context) []AuditEvent {
+	if events, ok := ctx.Value(auditPendingKey{}).(*[]AuditEvent); ok {
+		return *events
+	}
+	return nil
+}
+
+// AuditRecord is the row DBAuditSink writes, one per published event.
+type AuditRecord struct {
+	ID        uint `gorm:"primaryKey"`
+	Table     string
+	PKColumns string
+	PKValues  string
+	Actor     string
+	Mode      SoftDeleteMode
+	Restored  bool
+	CreatedAt time.Time
+}
+
+// DBAuditSink writes every event as an AuditRecord row through DB, for
+// setups that want soft-delete history queryable in the same database.
+// Migrate AuditRecord into DB before registering the sink.
+type DBAuditSink struct {
+	DB *gorm.DB
+}
+
+func NewDBAuditSink(db *gorm.DB) *DBAuditSink {
+	return &DBAuditSink{DB: db}
+}
+
+func (s *DBAuditSink) OnSoftDelete(ctx context.Context, event AuditEvent) error {
+	return s.write(ctx, event, false)
+}
+
+func (s *DBAuditSink) OnRestore(ctx context.Context, event AuditEvent) error {
+	return s.write(ctx, event, true)
+}
+
+func (s *DBAuditSink) write(ctx context.Context, event AuditEvent, restored bool) error {
+	return s.DB.WithContext(ctx).Create(&AuditRecord{
+		Table:     event.Table,
+		PKColumns: strings.Join(event.PKColumns, ","),
+		PKValues:  formatPKValues(event.PKValues),
+		Actor:     fmt.Sprint(event.Actor),
+		Mode:      event.Mode,
+		Restored:  restored,
+		CreatedAt: event.At,
+	}).Error
+}
+
+func formatPKValues(values [][]interface{}) string {
+	rows := make([]string, len(values))
+	for i, row := range values {
+		rows[i] = fmt.Sprint(row)
+	}
+	return strings.Join(rows, ";")
+}
+
+// LogAuditSink publishes events through a *log.Logger, for setups that
+// just want soft deletes visible in application logs. Logger defaults to
+// log.Default() when nil.
+type LogAuditSink struct {
+	Logger *log.Logger
+}
+
+func (s *LogAuditSink) OnSoftDelete(_ context.Context, event AuditEvent) error {
+	s.logger().Printf("soft delete: table=%s pk=%v actor=%v mode=%v at=%s", event.Table, event.PKValues, event.Actor, event.Mode, event.At)
+	return nil
+}
+
+func (s *LogAuditSink) OnRestore(_ context.Context, event AuditEvent) error {
+	s.logger().Printf("restore: table=%s pk=%v actor=%v mode=%v at=%s", event.Table, event.PKValues, event.Actor, event.Mode, event.At)
+	return nil
+}
+
+func (s *LogAuditSink) logger() *log.Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return log.Default()
+}
+
+// ChannelAuditSink publishes events onto a channel instead of storing them
+// itself, for CDC/outbox-style consumers that pull deletions off a queue.
+// If Events is full, the event is dropped rather than stalling the
+// transaction that produced it - size the buffer for your consumer.
+type ChannelAuditSink struct {
+	Events chan AuditEvent
+}
+
+func NewChannelAuditSink(buffer int) *ChannelAuditSink {
+	return &ChannelAuditSink{Events: make(chan AuditEvent, buffer)}
+}
+
+func (s *ChannelAuditSink) OnSoftDelete(_ context.Context, event AuditEvent) error {
+	return s.publish(event)
+}
+
+func (s *ChannelAuditSink) OnRestore(_ context.Context, event AuditEvent) error {
+	event.Restore = true
+	return s.publish(event)
+}
+
+func (s *ChannelAuditSink) publish(event AuditEvent) error {
+	select {
+	case s.Events <- event:
+	default:
+	}
+	return nil
+}
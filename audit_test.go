@@ -0,0 +1,143 @@
+package gorm_soft_delete
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AuditUser struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	DeletedAt DeletedAt
+}
+
+func TestRegisterAuditsDelete(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&AuditUser{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	sink := NewChannelAuditSink(1)
+	if err := Register(db, sink); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	user := AuditUser{Name: "turing"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	select {
+	case event := <-sink.Events:
+		if event.Restore {
+			t.Fatalf("expected a soft-delete event, got a restore event")
+		}
+		if event.Table != "audit_users" {
+			t.Fatalf("expected event.Table = audit_users, got %q", event.Table)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("db.Delete() did not publish an audit event")
+	}
+}
+
+func TestRegisterAuditsRestore(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&AuditUser{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	sink := NewChannelAuditSink(1)
+	if err := Register(db, sink); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	user := AuditUser{Name: "lovelace"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	<-sink.Events // drain the delete event
+
+	if err := Restore(db.Where("id = ?", user.ID), &AuditUser{}).Error; err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	select {
+	case event := <-sink.Events:
+		if !event.Restore {
+			t.Fatalf("expected a restore event")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Restore() did not publish an audit event")
+	}
+}
+
+// errSink always fails, to prove the publish callback genuinely runs
+// inside the write's transaction and not after it has already committed.
+type errSink struct{ err error }
+
+func (s errSink) OnSoftDelete(context.Context, AuditEvent) error { return s.err }
+func (s errSink) OnRestore(context.Context, AuditEvent) error    { return s.err }
+
+func TestRegisterFailingSinkRollsBackDelete(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&AuditUser{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	sinkErr := errors.New("sink unavailable")
+	if err := Register(db, errSink{err: sinkErr}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	user := AuditUser{Name: "hopper"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if err := db.Delete(&user).Error; !errors.Is(err, sinkErr) {
+		t.Fatalf("expected db.Delete() to surface the sink error, got %v", err)
+	}
+
+	if err := db.First(&AuditUser{}, user.ID).Error; err != nil {
+		t.Fatalf("expected delete to be rolled back, row should still be visible: %v", err)
+	}
+}
+
+func TestRegisterFailingSinkRollsBackRestore(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&AuditUser{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	user := AuditUser{Name: "kay"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	sinkErr := errors.New("sink unavailable")
+	if err := Register(db, errSink{err: sinkErr}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if err := Restore(db.Where("id = ?", user.ID), &AuditUser{}).Error; !errors.Is(err, sinkErr) {
+		t.Fatalf("expected Restore() to surface the sink error, got %v", err)
+	}
+
+	if err := db.First(&AuditUser{}, user.ID).Error; err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected restore to be rolled back, row should still be soft deleted, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package gorm_soft_delete
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// parseCascadeTag reads the `cascade` tag value, e.g.
+// `gorm:"softDelete:flag;cascade:associations"` or, to also soft delete
+// Many2Many join rows, `cascade:associations,join`.
+func parseCascadeTag(f *schema.Field) (cascade bool, joins bool) {
+	v, ok := f.TagSettings["CASCADE"]
+	if !ok {
+		return false, false
+	}
+
+	for _, part := range strings.Split(v, ",") {
+		switch strings.TrimSpace(part) {
+		case "associations":
+			cascade = true
+		case "join":
+			joins = true
+		}
+	}
+
+	return cascade, joins
+}
+
+// cascadeVisitedKey is the context key under which cascadeSoftDelete tracks
+// which relations it has already cascaded through for the current delete.
+// It is keyed by *schema.Relationship rather than by destination schema:
+// GORM caches one *schema.Schema (and its relations) per Go type, so two
+// distinct relations that happen to point at the same child schema - e.g.
+// Employees and Contractors both typed []Employee - are different
+// *schema.Relationship values and must each cascade independently. Keying
+// by relation still stops a genuinely self-referential relation (e.g. a
+// Comment's own Replies) from recursing forever, since revisiting that
+// same field produces the exact same cached *schema.Relationship.
+type cascadeVisitedKey struct{}
+
+func cascadeVisited(ctx context.Context) map[*schema.Relationship]bool {
+	if v, ok := ctx.Value(cascadeVisitedKey{}).(map[*schema.Relationship]bool); ok {
+		return v
+	}
+	return nil
+}
+
+// cascadeSoftDelete soft deletes the HasOne/HasMany associations (and,
+// when joins is true, Many2Many join rows) of the rows stmt is about to
+// soft delete. It is modeled on GORM's own DeleteBeforeAssociations
+// callback: conditions are built from the parent's primary-key values via
+// rel.ToQueryConditions and each child is deleted through its own
+// session-scoped *gorm.DB, so the child's own soft-delete (and cascade)
+// clauses run recursively. It only runs for soft deletes: an Unscoped
+// (hard) delete skips the cascade entirely, since ModifyStatement never
+// reaches this call in that case - see its `!stmt.Unscoped` guard.
+func cascadeSoftDelete(stmt *gorm.Statement, joins bool) {
+	if stmt.Schema == nil {
+		return
+	}
+
+	visited := cascadeVisited(stmt.Context)
+	if visited == nil {
+		visited = map[*schema.Relationship]bool{}
+		stmt.Context = context.WithValue(stmt.Context, cascadeVisitedKey{}, visited)
+	}
+
+	selectColumns, restricted := stmt.SelectAndOmitColumns(true, false)
+
+	for name, rel := range stmt.Schema.Relationships.Relations {
+		if restricted && !selectColumns[name] {
+			continue
+		}
+		if visited[rel] {
+			continue
+		}
+
+		switch rel.Type {
+		case schema.HasOne, schema.HasMany:
+			visited[rel] = true
+			cascadeHasOneOrMany(stmt, rel, name)
+		case schema.Many2Many:
+			if joins {
+				visited[rel] = true
+				cascadeMany2Many(stmt, rel)
+			}
+		}
+	}
+}
+
+func cascadeHasOneOrMany(stmt *gorm.Statement, rel *schema.Relationship, column string) {
+	conds := rel.ToQueryConditions(stmt.Context, stmt.ReflectValue)
+	for _, cond := range conds {
+		if in, ok := cond.(clause.IN); ok && len(in.Values) == 0 {
+			return
+		}
+	}
+
+	childModel := reflect.New(rel.FieldSchema.ModelType).Interface()
+	tx := stmt.DB.Session(&gorm.Session{NewDB: true, Context: stmt.Context}).Model(childModel)
+
+	if len(stmt.Selects) > 0 {
+		var selects []string
+		for _, s := range stmt.Selects {
+			if s == clause.Associations {
+				selects = append(selects, s)
+			} else if columnPrefix := column + "."; strings.HasPrefix(s, columnPrefix) {
+				selects = append(selects, strings.TrimPrefix(s, columnPrefix))
+			}
+		}
+		if len(selects) > 0 {
+			tx = tx.Select(selects)
+		}
+	}
+
+	stmt.DB.AddError(tx.Clauses(clause.Where{Exprs: conds}).Delete(childModel).Error)
+}
+
+func cascadeMany2Many(stmt *gorm.Statement, rel *schema.Relationship) {
+	var (
+		queryConds    []clause.Expression
+		foreignFields []*schema.Field
+		foreignKeys   []string
+		joinValue     = reflect.New(rel.JoinTable.ModelType).Interface()
+	)
+
+	for _, ref := range rel.References {
+		if ref.OwnPrimaryKey {
+			foreignFields = append(foreignFields, ref.PrimaryKey)
+			foreignKeys = append(foreignKeys, ref.ForeignKey.DBName)
+		} else if ref.PrimaryValue != "" {
+			queryConds = append(queryConds, clause.Eq{
+				Column: clause.Column{Table: rel.JoinTable.Table, Name: ref.ForeignKey.DBName},
+				Value:  ref.PrimaryValue,
+			})
+		}
+	}
+
+	_, foreignValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, foreignFields)
+	column, values := schema.ToQueryValues(rel.JoinTable.Table, foreignKeys, foreignValues)
+	if len(values) == 0 {
+		return
+	}
+	queryConds = append(queryConds, clause.IN{Column: column, Values: values})
+
+	tx := stmt.DB.Session(&gorm.Session{NewDB: true, Context: stmt.Context}).Model(joinValue).Table(rel.JoinTable.Table)
+	stmt.DB.AddError(tx.Clauses(clause.Where{Exprs: queryConds}).Delete(joinValue).Error)
+}
@@ -0,0 +1,112 @@
+package gorm_soft_delete
+
+import (
+	"testing"
+	"time"
+)
+
+// CascadeEmployee is pointed at by two distinct HasMany relations on
+// CascadeCompany (Employees and Contractors) - a non-cyclic "diamond" in
+// the relationship graph, since both relations share the same child
+// schema. Both must cascade independently.
+type CascadeEmployee struct {
+	ID           uint `gorm:"primaryKey"`
+	CompanyID    uint
+	ContractorOf uint
+	Name         string
+	DeletedAt    DeletedAt
+}
+
+type CascadeCompany struct {
+	ID          uint `gorm:"primaryKey"`
+	Name        string
+	Employees   []CascadeEmployee `gorm:"foreignKey:CompanyID"`
+	Contractors []CascadeEmployee `gorm:"foreignKey:ContractorOf"`
+	DeletedAt   DeletedAt         `gorm:"cascade:associations"`
+}
+
+func TestCascadeSoftDeleteDiamondRelations(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&CascadeCompany{}, &CascadeEmployee{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	company := CascadeCompany{
+		Name: "acme",
+		Employees: []CascadeEmployee{
+			{Name: "staff-1"},
+		},
+		Contractors: []CascadeEmployee{
+			{Name: "contractor-1"},
+		},
+	}
+	if err := db.Create(&company).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if err := db.Delete(&company).Error; err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	var remaining int64
+	if err := db.Model(&CascadeEmployee{}).Count(&remaining).Error; err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if remaining != 0 {
+		t.Fatalf("expected both Employees and Contractors to cascade soft delete, %d rows still visible", remaining)
+	}
+
+	var total int64
+	if err := db.Unscoped().Model(&CascadeEmployee{}).Count(&total).Error; err != nil {
+		t.Fatalf("unscoped count: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected 2 employee rows total, got %d", total)
+	}
+}
+
+// CascadeComment is self-referential (a comment's own Replies) - the case
+// the visited map must still guard against to avoid recursing forever.
+type CascadeComment struct {
+	ID        uint `gorm:"primaryKey"`
+	ParentID  *uint
+	Body      string
+	Replies   []CascadeComment `gorm:"foreignKey:ParentID"`
+	DeletedAt DeletedAt        `gorm:"cascade:associations"`
+}
+
+func TestCascadeSoftDeleteSelfReferential(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&CascadeComment{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	root := CascadeComment{Body: "root"}
+	if err := db.Create(&root).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	child := CascadeComment{Body: "child", ParentID: &root.ID}
+	if err := db.Create(&child).Error; err != nil {
+		t.Fatalf("create child: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- db.Delete(&root).Error }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("cascadeSoftDelete did not terminate for a self-referential relation")
+	}
+
+	var remaining int64
+	if err := db.Model(&CascadeComment{}).Count(&remaining).Error; err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if remaining != 0 {
+		t.Fatalf("expected root and child to be soft deleted, %d rows still visible", remaining)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/jinzhu/now"
 	"gorm.io/gorm"
@@ -12,6 +14,55 @@ import (
 	"gorm.io/gorm/schema"
 )
 
+// SoftDeleteMode controls what ModifyStatement writes on delete and checks
+// for on query, independent of which Go type backs the soft-delete field.
+// It is derived once from the `softDelete` tag and stored on each clause so
+// ModifyStatement never has to re-parse the tag per statement.
+type SoftDeleteMode int
+
+const (
+	// ModeTimestamp writes stmt.DB.NowFunc() (a time.Time) and filters with
+	// the field's zero value. This is the default, backwards-compatible
+	// behavior for a DeletedAt field.
+	ModeTimestamp SoftDeleteMode = iota
+	// ModeFlag treats the field as a plain boolean: false means "not
+	// deleted", true means "deleted". Selected with `softDelete:flag`.
+	ModeFlag
+	// ModeUnixSecond writes stmt.DB.NowFunc().Unix(). Selected with `softDelete:unix`.
+	ModeUnixSecond
+	// ModeUnixMilli writes stmt.DB.NowFunc().UnixMilli(). Selected with `softDelete:milli`.
+	ModeUnixMilli
+	// ModeUnixNano writes stmt.DB.NowFunc().UnixNano(). Selected with `softDelete:nano`.
+	ModeUnixNano
+)
+
+// parseSoftDeleteMode reads the `softDelete` tag value and maps it to a
+// SoftDeleteMode, falling back to the mode that matches the Go type the
+// tag was found on when the tag is absent or unrecognized.
+func parseSoftDeleteMode(f *schema.Field, fallback SoftDeleteMode) SoftDeleteMode {
+	switch f.TagSettings["SOFTDELETE"] {
+	case "flag":
+		return ModeFlag
+	case "unix":
+		return ModeUnixSecond
+	case "milli":
+		return ModeUnixMilli
+	case "nano":
+		return ModeUnixNano
+	default:
+		return fallback
+	}
+}
+
+// softDeleteContextKey is an unexported type for the context keys this
+// package defines, so they can't collide with keys set by other packages.
+type softDeleteContextKey string
+
+// GORM_CTX_DELETED_BY is the context key ModifyStatement looks up to find
+// the actor recorded into an ActorField column when a row is soft deleted,
+// e.g. db.WithContext(context.WithValue(ctx, GORM_CTX_DELETED_BY, userID)).
+const GORM_CTX_DELETED_BY softDeleteContextKey = "gorm:soft_delete:deleted_by"
+
 type DeletedAt sql.NullTime
 
 // Scan implements the Scanner interface.
@@ -47,31 +98,162 @@ func (n *DeletedAt) UnmarshalJSON(b []byte) error {
 }
 
 func (DeletedAt) QueryClauses(f *schema.Field) []clause.Interface {
-	softDeleteClause := SoftDeleteQueryClause{
-		Field:     f,
-		ZeroValue: parseZeroValueTag(f),
+	return []clause.Interface{newSoftDeleteQueryClause(f, parseSoftDeleteMode(f, ModeTimestamp))}
+}
+
+func (DeletedAt) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{newSoftDeleteUpdateClause(f, parseSoftDeleteMode(f, ModeTimestamp))}
+}
+
+func (DeletedAt) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{newSoftDeleteDeleteClause(f, parseSoftDeleteMode(f, ModeTimestamp))}
+}
+
+// IsDeleted backs a soft-delete field with a plain boolean flag column
+// instead of a timestamp. Tag the field `gorm:"softDelete:flag"` (this is
+// also the default mode for this type when the tag is omitted).
+type IsDeleted bool
+
+// Scan implements the Scanner interface.
+func (n *IsDeleted) Scan(value interface{}) error {
+	var b sql.NullBool
+	if err := b.Scan(value); err != nil {
+		return err
 	}
+	*n = IsDeleted(b.Bool)
+	return nil
+}
 
-	if v := f.TagSettings["ACTORFIELD"]; len(v) >= 1 {
-		softDeleteClause.ActorField = f.Schema.LookUpField(v)
+// Value implements the driver Valuer interface.
+func (n IsDeleted) Value() (driver.Value, error) {
+	return bool(n), nil
+}
+
+func (IsDeleted) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{newSoftDeleteQueryClause(f, parseSoftDeleteMode(f, ModeFlag))}
+}
+
+func (IsDeleted) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{newSoftDeleteUpdateClause(f, parseSoftDeleteMode(f, ModeFlag))}
+}
+
+func (IsDeleted) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{newSoftDeleteDeleteClause(f, parseSoftDeleteMode(f, ModeFlag))}
+}
+
+// DeletedAtUnix backs a soft-delete field with an integer unix timestamp
+// column rather than a time.Time one. Combine with `gorm:"softDelete:milli"`
+// or `softDelete:nano` to pick the precision written on delete; with no
+// suffix (or `softDelete:unix`) it writes whole seconds.
+type DeletedAtUnix uint
+
+func (DeletedAtUnix) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{newSoftDeleteQueryClause(f, parseSoftDeleteMode(f, ModeUnixSecond))}
+}
+
+func (DeletedAtUnix) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{newSoftDeleteUpdateClause(f, parseSoftDeleteMode(f, ModeUnixSecond))}
+}
+
+func (DeletedAtUnix) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{newSoftDeleteDeleteClause(f, parseSoftDeleteMode(f, ModeUnixSecond))}
+}
+
+// zeroValueKind distinguishes how a `ZEROVALUE` tag was written, so it can
+// be resolved into a correctly-typed value instead of always being treated
+// as a time.Time sentinel string.
+type zeroValueKind int
+
+const (
+	// zeroValueDefault means there was no (usable) ZEROVALUE tag; the mode
+	// decides the zero value instead (see softDeleteZeroValue).
+	zeroValueDefault zeroValueKind = iota
+	// zeroValueNull comes from `ZEROVALUE:null`, for schemas that use NULL
+	// itself as "not deleted" rather than a time.Time zero value.
+	zeroValueNull
+	// zeroValueTime comes from a tag value `now` can parse as a time, e.g.
+	// `ZEROVALUE:0001-01-01`, for databases that disallow NULL on the
+	// column and need an explicit sentinel timestamp instead.
+	zeroValueTime
+	// zeroValueString comes from any other tag value, passed through
+	// verbatim as the WHERE/restore literal.
+	zeroValueString
+)
+
+type zeroValue struct {
+	kind zeroValueKind
+	str  string
+}
+
+// parseZeroValueTag classifies a field's `ZEROVALUE` tag (absent, null,
+// now.Parse-able time, or any other string) instead of accepting only
+// now.Parse-able time strings. Every tag value other than null is kept as
+// its literal string; a typed false/0 zero value for flag and unix-int
+// fields comes from the mode when the tag is absent (see softDeleteZeroValue).
+func parseZeroValueTag(f *schema.Field) zeroValue {
+	v, ok := f.TagSettings["ZEROVALUE"]
+	if !ok {
+		return zeroValue{kind: zeroValueDefault}
 	}
 
-	return []clause.Interface{softDeleteClause}
+	if strings.EqualFold(v, "null") {
+		return zeroValue{kind: zeroValueNull}
+	}
+
+	if _, err := now.Parse(v); err == nil {
+		return zeroValue{kind: zeroValueTime, str: v}
+	}
+
+	return zeroValue{kind: zeroValueString, str: v}
 }
 
-func parseZeroValueTag(f *schema.Field) sql.NullString {
-	if v, ok := f.TagSettings["ZEROVALUE"]; ok {
-		if _, err := now.Parse(v); err == nil {
-			return sql.NullString{String: v, Valid: true}
-		}
+// softDeleteZeroValue returns the value ModifyStatement should filter
+// queries on (and Restore should write back) to mean "not deleted". An
+// explicit ZEROVALUE tag always wins; otherwise the mode picks a sensible
+// default: NULL for a timestamp column, false for a flag, 0 for a unix-int
+// column. A time-parseable sentinel (zeroValueTime) is passed through as
+// the literal tag string rather than the time.Time now.Parse produced:
+// now.Parse("0001-01-01") returns Go's zero time.Time, and database/sql
+// drivers special-case Time.IsZero() values on serialization, so binding
+// the parsed time produced a literal that didn't match a column actually
+// storing the "0001-01-01 00:00:00" sentinel via a NOT NULL DEFAULT.
+func softDeleteZeroValue(mode SoftDeleteMode, zv zeroValue) interface{} {
+	switch zv.kind {
+	case zeroValueNull:
+		return nil
+	case zeroValueTime, zeroValueString:
+		return zv.str
+	}
+
+	switch mode {
+	case ModeFlag:
+		return false
+	case ModeUnixSecond, ModeUnixMilli, ModeUnixNano:
+		return 0
+	default:
+		return sql.NullTime{}
 	}
-	return sql.NullString{Valid: false}
 }
 
 type SoftDeleteQueryClause struct {
-	ZeroValue  sql.NullString
+	ZeroValue  zeroValue
 	Field      *schema.Field
 	ActorField *schema.Field
+	Mode       SoftDeleteMode
+}
+
+func newSoftDeleteQueryClause(f *schema.Field, mode SoftDeleteMode) SoftDeleteQueryClause {
+	sd := SoftDeleteQueryClause{
+		Field:     f,
+		ZeroValue: parseZeroValueTag(f),
+		Mode:      mode,
+	}
+
+	if v := f.TagSettings["ACTORFIELD"]; len(v) >= 1 {
+		sd.ActorField = f.Schema.LookUpField(v)
+	}
+
+	return sd
 }
 
 func (sd SoftDeleteQueryClause) Name() string {
@@ -85,7 +267,7 @@ func (sd SoftDeleteQueryClause) MergeClause(*clause.Clause) {
 }
 
 func (sd SoftDeleteQueryClause) ModifyStatement(stmt *gorm.Statement) {
-	if _, ok := stmt.Clauses["soft_delete_enabled"]; !ok && !stmt.Statement.Unscoped {
+	if _, ok := stmt.Clauses["soft_delete_enabled"]; !ok && !stmt.Statement.Unscoped && !includeDeleted(stmt.Context) {
 		if c, ok := stmt.Clauses["WHERE"]; ok {
 			if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) >= 1 {
 				for _, expr := range where.Exprs {
@@ -100,30 +282,32 @@ func (sd SoftDeleteQueryClause) ModifyStatement(stmt *gorm.Statement) {
 		}
 
 		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
-			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: sd.ZeroValue},
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: softDeleteZeroValue(sd.Mode, sd.ZeroValue)},
 		}})
 
 		stmt.Clauses["soft_delete_enabled"] = clause.Clause{}
 	}
 }
 
-func (DeletedAt) UpdateClauses(f *schema.Field) []clause.Interface {
-	softDeleteClause := SoftDeleteUpdateClause{
+type SoftDeleteUpdateClause struct {
+	ZeroValue  zeroValue
+	Field      *schema.Field
+	ActorField *schema.Field
+	Mode       SoftDeleteMode
+}
+
+func newSoftDeleteUpdateClause(f *schema.Field, mode SoftDeleteMode) SoftDeleteUpdateClause {
+	sd := SoftDeleteUpdateClause{
 		Field:     f,
 		ZeroValue: parseZeroValueTag(f),
+		Mode:      mode,
 	}
 
 	if v := f.TagSettings["ACTORFIELD"]; len(v) >= 1 {
-		softDeleteClause.ActorField = f.Schema.LookUpField(v)
+		sd.ActorField = f.Schema.LookUpField(v)
 	}
 
-	return []clause.Interface{softDeleteClause}
-}
-
-type SoftDeleteUpdateClause struct {
-	ZeroValue  sql.NullString
-	Field      *schema.Field
-	ActorField *schema.Field
+	return sd
 }
 
 func (sd SoftDeleteUpdateClause) Name() string {
@@ -138,27 +322,54 @@ func (sd SoftDeleteUpdateClause) MergeClause(*clause.Clause) {
 
 func (sd SoftDeleteUpdateClause) ModifyStatement(stmt *gorm.Statement) {
 	if stmt.SQL.Len() == 0 && !stmt.Statement.Unscoped {
-		SoftDeleteQueryClause(sd).ModifyStatement(stmt)
+		sd.asQueryClause().ModifyStatement(stmt)
 	}
 }
 
-func (DeletedAt) DeleteClauses(f *schema.Field) []clause.Interface {
-	softDeleteClause := SoftDeleteDeleteClause{
+func (sd SoftDeleteUpdateClause) asQueryClause() SoftDeleteQueryClause {
+	return SoftDeleteQueryClause{ZeroValue: sd.ZeroValue, Field: sd.Field, ActorField: sd.ActorField, Mode: sd.Mode}
+}
+
+type SoftDeleteDeleteClause struct {
+	ZeroValue  zeroValue
+	Field      *schema.Field
+	ActorField *schema.Field
+	Mode       SoftDeleteMode
+	// DeletedAtField is the companion field named by the `DeletedAtField`
+	// tag, e.g. `gorm:"softDelete:flag;DeletedAtField:DeletedAt"`. When set,
+	// ModifyStatement writes the current time into it alongside flipping the
+	// flag column, for mixed-mode deployments that want both a fast boolean
+	// check and a "when" timestamp. Queries still filter on Field alone.
+	DeletedAtField *schema.Field
+	// Cascade, when set via `gorm:"softDelete:...;cascade:associations"`,
+	// makes ModifyStatement also soft delete the model's HasOne/HasMany
+	// associations (and CascadeJoins additionally its Many2Many join rows).
+	Cascade      bool
+	CascadeJoins bool
+}
+
+func newSoftDeleteDeleteClause(f *schema.Field, mode SoftDeleteMode) SoftDeleteDeleteClause {
+	sd := SoftDeleteDeleteClause{
 		Field:     f,
 		ZeroValue: parseZeroValueTag(f),
+		Mode:      mode,
 	}
 
 	if v := f.TagSettings["ACTORFIELD"]; len(v) >= 1 {
-		softDeleteClause.ActorField = f.Schema.LookUpField(v)
+		sd.ActorField = f.Schema.LookUpField(v)
 	}
 
-	return []clause.Interface{softDeleteClause}
+	if v := f.TagSettings["DELETEDATFIELD"]; len(v) >= 1 {
+		sd.DeletedAtField = f.Schema.LookUpField(v)
+	}
+
+	sd.Cascade, sd.CascadeJoins = parseCascadeTag(f)
+
+	return sd
 }
 
-type SoftDeleteDeleteClause struct {
-	ZeroValue  sql.NullString
-	Field      *schema.Field
-	ActorField *schema.Field
+func (sd SoftDeleteDeleteClause) asQueryClause() SoftDeleteQueryClause {
+	return SoftDeleteQueryClause{ZeroValue: sd.ZeroValue, Field: sd.Field, ActorField: sd.ActorField, Mode: sd.Mode}
 }
 
 func (sd SoftDeleteDeleteClause) Name() string {
@@ -171,12 +382,30 @@ func (sd SoftDeleteDeleteClause) Build(clause.Builder) {
 func (sd SoftDeleteDeleteClause) MergeClause(*clause.Clause) {
 }
 
+// softDeleteValue returns what ModifyStatement writes into the soft-delete
+// column on delete, which depends on the clause's mode: a time.Time for
+// ModeTimestamp, a bool for ModeFlag, or an int64 unix value otherwise.
+func softDeleteValue(mode SoftDeleteMode, now func() time.Time) interface{} {
+	switch mode {
+	case ModeFlag:
+		return true
+	case ModeUnixSecond:
+		return now().Unix()
+	case ModeUnixMilli:
+		return now().UnixMilli()
+	case ModeUnixNano:
+		return now().UnixNano()
+	default:
+		return now()
+	}
+}
+
 func (sd SoftDeleteDeleteClause) ModifyStatement(stmt *gorm.Statement) {
 	if stmt.SQL.Len() == 0 && !stmt.Statement.Unscoped {
-		curTime := stmt.DB.NowFunc()
+		curValue := softDeleteValue(sd.Mode, stmt.DB.NowFunc)
 
 		clauseSet := clause.Set{
-			{Column: clause.Column{Name: sd.Field.DBName}, Value: curTime},
+			{Column: clause.Column{Name: sd.Field.DBName}, Value: curValue},
 		}
 
 		if sd.ActorField != nil {
@@ -186,8 +415,16 @@ func (sd SoftDeleteDeleteClause) ModifyStatement(stmt *gorm.Statement) {
 			}
 		}
 
+		if sd.DeletedAtField != nil {
+			deletedAt := stmt.DB.NowFunc()
+			clauseSet = append(clauseSet, clause.Assignment{Column: clause.Column{Name: sd.DeletedAtField.DBName}, Value: deletedAt})
+			stmt.SetColumn(sd.DeletedAtField.DBName, deletedAt, true)
+		}
+
 		stmt.AddClause(clauseSet)
-		stmt.SetColumn(sd.Field.DBName, curTime, true)
+		stmt.SetColumn(sd.Field.DBName, curValue, true)
+
+		var pkValues [][]interface{}
 
 		if stmt.Schema != nil {
 			_, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
@@ -197,6 +434,10 @@ func (sd SoftDeleteDeleteClause) ModifyStatement(stmt *gorm.Statement) {
 				stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
 			}
 
+			for _, row := range queryValues {
+				pkValues = append(pkValues, row)
+			}
+
 			if stmt.ReflectValue.CanAddr() && stmt.Dest != stmt.Model && stmt.Model != nil {
 				_, queryValues = schema.GetIdentityFieldValuesMap(stmt.Context, reflect.ValueOf(stmt.Model), stmt.Schema.PrimaryFields)
 				column, values = schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
@@ -205,9 +446,22 @@ func (sd SoftDeleteDeleteClause) ModifyStatement(stmt *gorm.Statement) {
 					stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
 				}
 			}
+
+			stmt.Context = withPendingAuditEvent(stmt.Context, AuditEvent{
+				Table:     stmt.Table,
+				PKColumns: stmt.Schema.PrimaryFieldDBNames,
+				PKValues:  pkValues,
+				Actor:     stmt.Context.Value(GORM_CTX_DELETED_BY),
+				Mode:      sd.Mode,
+				At:        stmt.DB.NowFunc(),
+			})
+		}
+
+		if sd.Cascade {
+			cascadeSoftDelete(stmt, sd.CascadeJoins)
 		}
 
-		SoftDeleteQueryClause(sd).ModifyStatement(stmt)
+		sd.asQueryClause().ModifyStatement(stmt)
 		stmt.AddClauseIfNotExists(clause.Update{})
 		stmt.Build(stmt.DB.Callback().Update().Clauses...)
 	}
@@ -0,0 +1,74 @@
+package gorm_soft_delete
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDB opens a fresh in-memory SQLite database for a single test. Each
+// call gets its own database (file::memory: with a unique DSN would share a
+// cache across connections), so tests can run in parallel without
+// interfering with each other.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	return db
+}
+
+// FlagUser is a ModeFlag-backed model used to exercise IsDeleted's
+// Scan/Value round trip through a real database/sql driver.
+type FlagUser struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	IsDeleted IsDeleted
+}
+
+func TestIsDeletedRoundTrip(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&FlagUser{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	user := FlagUser{Name: "ada"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// A plain read of an undeleted row must scan IsDeleted's underlying
+	// driver.Value (an int64 on SQLite) without error.
+	var found FlagUser
+	if err := db.First(&found, user.ID).Error; err != nil {
+		t.Fatalf("first: %v", err)
+	}
+	if found.IsDeleted {
+		t.Fatalf("freshly created row should not be flagged deleted")
+	}
+
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	// db.First should not find the row now that IsDeleted is set...
+	if err := db.First(&FlagUser{}, user.ID).Error; err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected ErrRecordNotFound for soft-deleted row, got %v", err)
+	}
+
+	// ...but Unscoped must still be able to scan the flagged-true row back.
+	var deleted FlagUser
+	if err := db.Unscoped().First(&deleted, user.ID).Error; err != nil {
+		t.Fatalf("unscoped first: %v", err)
+	}
+	if !deleted.IsDeleted {
+		t.Fatalf("expected IsDeleted to scan back true after delete")
+	}
+}
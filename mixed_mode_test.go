@@ -0,0 +1,42 @@
+package gorm_soft_delete
+
+import "testing"
+
+// MixedUser combines a fast boolean flag with a companion "when" timestamp
+// via DeletedAtField - `gorm:"softDelete:flag;DeletedAtField:DeletedAt"`.
+// Tag settings split on ';', not ',' - the separator used in an earlier
+// version of this doc comment, which silently merged the whole value into
+// TagSettings["SOFTDELETE"] and left DeletedAtField unset.
+type MixedUser struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	IsDeleted IsDeleted `gorm:"softDelete:flag;DeletedAtField:DeletedAt"`
+	DeletedAt DeletedAt
+}
+
+func TestMixedModeWritesCompanionDeletedAtField(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&MixedUser{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	user := MixedUser{Name: "ada"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	var deleted MixedUser
+	if err := db.Unscoped().First(&deleted, user.ID).Error; err != nil {
+		t.Fatalf("unscoped first: %v", err)
+	}
+	if !deleted.IsDeleted {
+		t.Fatalf("expected IsDeleted flag to be set")
+	}
+	if !deleted.DeletedAt.Valid {
+		t.Fatalf("expected companion DeletedAt to be written alongside the flag, got %+v", deleted.DeletedAt)
+	}
+}
@@ -0,0 +1,152 @@
+package gorm_soft_delete
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// includeDeletedKey is the context key WithIncludeDeleted sets.
+type includeDeletedKey struct{}
+
+// WithIncludeDeleted returns a context that makes a single query include
+// soft-deleted rows, i.e. skip the `deleted_at = zero` predicate, without
+// reaching for Unscoped() on the *gorm.DB chain. It exists for generic
+// repository layers that only carry a context.Context and can't thread an
+// Unscoped() call through to the query.
+func WithIncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+func includeDeleted(ctx context.Context) bool {
+	v, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return v
+}
+
+// Restoring is a clause marker for db.Clauses(soft_delete.Restoring{}):
+// it makes SoftDeleteQueryClause skip its soft-delete predicate the same
+// way Unscoped() does. It sets Statement.Unscoped directly rather than
+// pre-populating the "soft_delete_enabled" clause marker the query/update
+// clauses use for the same purpose: that marker also tells GORM's own
+// checkMissingWhereConditions to expect a second, auto-injected WHERE
+// expression, and Restoring suppresses that expression without adding it,
+// which made every caller-supplied condition get miscounted as "no real
+// condition" and fail with ErrMissingWhereClause. Most callers should just
+// use Restore below instead of this directly.
+type Restoring struct{}
+
+func (Restoring) Name() string { return "" }
+
+func (Restoring) Build(clause.Builder) {}
+
+func (Restoring) MergeClause(*clause.Clause) {}
+
+func (Restoring) ModifyStatement(stmt *gorm.Statement) {
+	stmt.Unscoped = true
+}
+
+// Restore clears a model's soft-delete field(s) back to "not deleted" -
+// the opposite of a soft Delete - so callers don't have to hand-write
+// `UPDATE ... SET deleted_at = NULL`. Attach any filtering conditions to db
+// beforehand, the same way you would for Delete:
+//
+//	soft_delete.Restore(db.Where("id = ?", 1), &User{})
+//
+// Every schema field backed by DeletedAt, IsDeleted or DeletedAtUnix is
+// reset to its zero value (honoring a `ZEROVALUE` tag when configured),
+// and an ActorField, if present, is cleared alongside it.
+func Restore(db *gorm.DB, models ...interface{}) *gorm.DB {
+	base := db.Session(&gorm.Session{})
+	result := base.Clauses(Restoring{})
+
+	for _, model := range models {
+		// Each model gets its own session derived from base, rather than
+		// chaining off the previous iteration's already-executed tx: once
+		// an Updates() call has run, its Statement.Table is populated, and
+		// gorm's Model() only fills Table in when it's still empty - so
+		// reusing that tx for a second model would run its UPDATE against
+		// the first model's table instead.
+		tx := base.Session(&gorm.Session{}).Clauses(Restoring{})
+
+		stmt := &gorm.Statement{DB: tx}
+		if err := stmt.Parse(model); err != nil {
+			tx.AddError(err)
+			return tx
+		}
+
+		assignments := map[string]interface{}{}
+		var mode SoftDeleteMode
+		restoringAnyField := false
+		for _, f := range stmt.Schema.Fields {
+			if !isSoftDeleteField(f) {
+				continue
+			}
+
+			restoringAnyField = true
+			mode = softDeleteFieldMode(f)
+			assignments[f.DBName] = softDeleteRestoreValue(f)
+
+			if v := f.TagSettings["ACTORFIELD"]; len(v) >= 1 {
+				if actor := stmt.Schema.LookUpField(v); actor != nil {
+					assignments[actor.DBName] = nil
+				}
+			}
+		}
+
+		if len(assignments) == 0 {
+			continue
+		}
+
+		restoreCtx := tx.Statement.Context
+		if restoringAnyField {
+			restoreCtx = withPendingAuditEvent(restoreCtx, AuditEvent{
+				Table:   stmt.Table,
+				Mode:    mode,
+				At:      tx.NowFunc(),
+				Restore: true,
+			})
+		}
+
+		result = tx.WithContext(restoreCtx).Model(model).Updates(assignments)
+		if result.Error != nil {
+			return result
+		}
+	}
+
+	return result
+}
+
+func isSoftDeleteField(f *schema.Field) bool {
+	switch f.FieldType {
+	case reflect.TypeOf(DeletedAt{}), reflect.TypeOf(IsDeleted(false)), reflect.TypeOf(DeletedAtUnix(0)):
+		return true
+	default:
+		return false
+	}
+}
+
+// softDeleteFieldMode resolves a soft-delete field's mode the same way
+// newSoftDeleteDeleteClause does, defaulting on the Go type backing it.
+func softDeleteFieldMode(f *schema.Field) SoftDeleteMode {
+	var fallback SoftDeleteMode
+	switch f.FieldType {
+	case reflect.TypeOf(IsDeleted(false)):
+		fallback = ModeFlag
+	case reflect.TypeOf(DeletedAtUnix(0)):
+		fallback = ModeUnixSecond
+	default:
+		fallback = ModeTimestamp
+	}
+
+	return parseSoftDeleteMode(f, fallback)
+}
+
+// softDeleteRestoreValue returns the "not deleted" value Restore writes
+// back into a soft-delete field, using the same mode/ZEROVALUE resolution
+// as the query side so a restored row is invisible to neither.
+func softDeleteRestoreValue(f *schema.Field) interface{} {
+	return softDeleteZeroValue(softDeleteFieldMode(f), parseZeroValueTag(f))
+}
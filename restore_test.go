@@ -0,0 +1,89 @@
+package gorm_soft_delete
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type RestoreUser struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	DeletedAt DeletedAt
+}
+
+func TestRestore(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&RestoreUser{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	user := RestoreUser{Name: "grace"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := db.First(&RestoreUser{}, user.ID).Error; err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected row to be soft deleted, got %v", err)
+	}
+
+	// Restore's own doc example: a caller-supplied condition plus Restore
+	// must not trip gorm's checkMissingWhereConditions.
+	if err := Restore(db.Where("id = ?", user.ID), &RestoreUser{}).Error; err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	var restored RestoreUser
+	if err := db.First(&restored, user.ID).Error; err != nil {
+		t.Fatalf("expected row to be visible again after restore: %v", err)
+	}
+	if !restored.DeletedAt.Time.IsZero() || restored.DeletedAt.Valid {
+		t.Fatalf("expected DeletedAt to be cleared, got %+v", restored.DeletedAt)
+	}
+}
+
+type RestoreWidget struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	DeletedAt DeletedAt
+}
+
+// TestRestoreMultipleModels guards against Restore chaining each model's
+// Updates() off the previous model's already-executed tx, which leaves the
+// later models' UPDATE running against the first model's table (Model()
+// only fills in Statement.Table when it's still empty).
+func TestRestoreMultipleModels(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&RestoreUser{}, &RestoreWidget{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	user := RestoreUser{Name: "ada"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	widget := RestoreWidget{Name: "gear"}
+	if err := db.Create(&widget).Error; err != nil {
+		t.Fatalf("create widget: %v", err)
+	}
+
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("delete user: %v", err)
+	}
+	if err := db.Delete(&widget).Error; err != nil {
+		t.Fatalf("delete widget: %v", err)
+	}
+
+	if err := Restore(db.Where("id = ?", 1), &RestoreUser{}, &RestoreWidget{}).Error; err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	if err := db.First(&RestoreUser{}, user.ID).Error; err != nil {
+		t.Fatalf("expected user to be restored: %v", err)
+	}
+	if err := db.First(&RestoreWidget{}, widget.ID).Error; err != nil {
+		t.Fatalf("expected widget to be restored, got %v (second model's UPDATE likely ran against the first model's table)", err)
+	}
+}
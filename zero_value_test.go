@@ -0,0 +1,53 @@
+package gorm_soft_delete
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// ZeroValueUser uses a NOT NULL DeletedAt column with a DB-level default
+// sentinel instead of NULL. The ZEROVALUE tag must match the literal text
+// the default actually stores - the query/restore side compares against
+// that literal string, not the time.Time now.Parse produces for it, which
+// is Go's zero time and gets special-cased by database/sql drivers on
+// serialization, so it wouldn't match what's actually stored.
+type ZeroValueUser struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	DeletedAt DeletedAt `gorm:"ZEROVALUE:0001-01-01 00:00:00;not null;default:'0001-01-01 00:00:00'"`
+}
+
+func TestZeroValueTimeSentinel(t *testing.T) {
+	db := testDB(t)
+	if err := db.AutoMigrate(&ZeroValueUser{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	user := ZeroValueUser{Name: "ada"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// A freshly created row must be visible to a plain query: its stored
+	// DeletedAt is the DB-level default sentinel, and the query predicate
+	// needs to compare against that same literal.
+	var found ZeroValueUser
+	if err := db.First(&found, user.ID).Error; err != nil {
+		t.Fatalf("expected freshly created row to be visible, got %v", err)
+	}
+
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := db.First(&ZeroValueUser{}, user.ID).Error; err != gorm.ErrRecordNotFound {
+		t.Fatalf("expected soft-deleted row to be hidden, got %v", err)
+	}
+
+	if err := Restore(db.Where("id = ?", user.ID), &ZeroValueUser{}).Error; err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if err := db.First(&ZeroValueUser{}, user.ID).Error; err != nil {
+		t.Fatalf("expected row to be visible again after restore, got %v", err)
+	}
+}